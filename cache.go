@@ -0,0 +1,108 @@
+package toggletown
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache persists the last-known-good flags snapshot so a Client can recover
+// from a transient API outage at startup instead of failing outright.
+// Implementations beyond FileCache (e.g. Redis) live in their own
+// subpackages behind this interface, so the core module doesn't have to
+// depend on their client libraries. See cache/redis for an example.
+type Cache interface {
+	Load() (FlagsResponse, time.Time, error)
+	Save(flagsResp FlagsResponse, lastUpdatedAt time.Time) error
+}
+
+// CacheEnvelope is the on-the-wire shape a Cache implementation should
+// persist: the flags snapshot plus the timestamp it was fetched at.
+// Exported so out-of-tree Cache implementations (e.g. cache/redis) can
+// share it instead of redefining their own.
+type CacheEnvelope struct {
+	Flags         map[string]FlagConfig `json:"flags"`
+	LastUpdatedAt time.Time             `json:"lastUpdatedAt"`
+}
+
+// FileCache persists flags to a JSON file, writing atomically via a temp
+// file plus rename so a crash mid-write can't corrupt the cache.
+type FileCache struct {
+	Path string
+}
+
+// Load reads and parses the cache file at Path.
+func (fc *FileCache) Load() (FlagsResponse, time.Time, error) {
+	data, err := os.ReadFile(fc.Path)
+	if err != nil {
+		return FlagsResponse{}, time.Time{}, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var env CacheEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return FlagsResponse{}, time.Time{}, fmt.Errorf("failed to parse cache file: %w", err)
+	}
+	return FlagsResponse{Flags: env.Flags}, env.LastUpdatedAt, nil
+}
+
+// Save writes flagsResp to Path, replacing any existing file atomically.
+func (fc *FileCache) Save(flagsResp FlagsResponse, lastUpdatedAt time.Time) error {
+	data, err := json.Marshal(CacheEnvelope{Flags: flagsResp.Flags, LastUpdatedAt: lastUpdatedAt})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fc.Path), ".toggletown-cache-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), fc.Path); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
+
+// loadFromCache populates c.flags from the configured cache, returning
+// whether it succeeded.
+func (c *Client) loadFromCache() bool {
+	flagsResp, lastUpdatedAt, err := c.cache.Load()
+	if err != nil {
+		return false
+	}
+
+	c.mu.Lock()
+	c.flags = flagsResp.Flags
+	c.lastUpdatedAt = lastUpdatedAt
+	c.staleFired = false
+	c.mu.Unlock()
+	c.checkStaleness()
+	return true
+}
+
+// saveToCacheAsync persists a freshly fetched snapshot without blocking the
+// caller. It's tracked via c.wg so Close() waits for an in-flight save
+// instead of racing process exit. Failures are reported via OnError, not
+// returned.
+func (c *Client) saveToCacheAsync(flagsResp FlagsResponse, lastUpdatedAt time.Time) {
+	if c.cache == nil {
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if err := c.cache.Save(flagsResp, lastUpdatedAt); err != nil && c.onError != nil {
+			c.onError(fmt.Errorf("failed to persist cache: %w", err))
+		}
+	}()
+}
@@ -0,0 +1,49 @@
+// Package redis implements toggletown.Cache on top of Redis, so the core
+// module doesn't have to depend on a Redis client for applications that
+// don't need it.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	toggletown "github.com/toggletown/sdk-go"
+)
+
+// Cache persists flags to a single Redis key as a JSON-encoded
+// toggletown.CacheEnvelope.
+type Cache struct {
+	Client *goredis.Client
+	Key    string
+}
+
+// Load reads and parses the cache entry at Key.
+func (rc *Cache) Load() (toggletown.FlagsResponse, time.Time, error) {
+	data, err := rc.Client.Get(context.Background(), rc.Key).Bytes()
+	if err != nil {
+		return toggletown.FlagsResponse{}, time.Time{}, fmt.Errorf("failed to read redis cache: %w", err)
+	}
+
+	var env toggletown.CacheEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return toggletown.FlagsResponse{}, time.Time{}, fmt.Errorf("failed to parse redis cache: %w", err)
+	}
+	return toggletown.FlagsResponse{Flags: env.Flags}, env.LastUpdatedAt, nil
+}
+
+// Save writes flagsResp to Key, replacing any existing entry.
+func (rc *Cache) Save(flagsResp toggletown.FlagsResponse, lastUpdatedAt time.Time) error {
+	data, err := json.Marshal(toggletown.CacheEnvelope{Flags: flagsResp.Flags, LastUpdatedAt: lastUpdatedAt})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+
+	if err := rc.Client.Set(context.Background(), rc.Key, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to write redis cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package toggletown
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCacheRoundTrip(t *testing.T) {
+	t.Run("save then load returns what was saved", func(t *testing.T) {
+		cache := &FileCache{Path: filepath.Join(t.TempDir(), "cache.json")}
+		flagsResp := FlagsResponse{Flags: map[string]FlagConfig{"a": {Key: "a", Enabled: true}}}
+		lastUpdatedAt := time.Now().Truncate(time.Second)
+
+		assert.NoError(t, cache.Save(flagsResp, lastUpdatedAt))
+
+		got, gotTime, err := cache.Load()
+		assert.NoError(t, err)
+		assert.True(t, got.Flags["a"].Enabled)
+		assert.True(t, lastUpdatedAt.Equal(gotTime))
+	})
+
+	t.Run("load without a prior save is an error", func(t *testing.T) {
+		cache := &FileCache{Path: filepath.Join(t.TempDir(), "missing.json")}
+		_, _, err := cache.Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("save does not leave a temp file behind", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := &FileCache{Path: filepath.Join(dir, "cache.json")}
+		assert.NoError(t, cache.Save(FlagsResponse{}, time.Now()))
+
+		entries, err := filepath.Glob(filepath.Join(dir, "*"))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
+
+type stubCache struct {
+	saved chan struct{}
+}
+
+func (s *stubCache) Load() (FlagsResponse, time.Time, error) {
+	return FlagsResponse{}, time.Time{}, assert.AnError
+}
+
+func (s *stubCache) Save(flagsResp FlagsResponse, lastUpdatedAt time.Time) error {
+	close(s.saved)
+	return nil
+}
+
+func TestSaveToCacheAsyncTrackedByWaitGroup(t *testing.T) {
+	cache := &stubCache{saved: make(chan struct{})}
+	c := NewClient("key", &Config{Cache: cache})
+
+	c.saveToCacheAsync(FlagsResponse{}, time.Now())
+	c.wg.Wait()
+
+	select {
+	case <-cache.saved:
+	default:
+		t.Fatal("expected Save to have been called before wg.Wait returned")
+	}
+}
+
+func TestSaveToCacheAsyncNoopWithoutCache(t *testing.T) {
+	c := NewClient("key", nil)
+	c.saveToCacheAsync(FlagsResponse{}, time.Now())
+	c.wg.Wait()
+}
+
+func TestLoadFromCache(t *testing.T) {
+	cache := &FileCache{Path: filepath.Join(t.TempDir(), "cache.json")}
+	lastUpdatedAt := time.Now().Truncate(time.Second)
+	assert.NoError(t, cache.Save(FlagsResponse{Flags: map[string]FlagConfig{"a": {Key: "a", Enabled: true}}}, lastUpdatedAt))
+
+	c := NewClient("key", &Config{Cache: cache})
+	assert.True(t, c.loadFromCache())
+
+	config, ok := c.getFlagConfig("a")
+	assert.True(t, ok)
+	assert.True(t, config.Enabled)
+	assert.True(t, lastUpdatedAt.Equal(c.GetLastUpdatedAt()))
+}
@@ -15,9 +15,8 @@
 package toggletown
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -32,6 +31,7 @@ const (
 
 // Rule represents a targeting rule
 type Rule struct {
+	ID         string      `json:"id,omitempty"`
 	Attribute  string      `json:"attribute"`
 	Operator   string      `json:"operator"`
 	Value      interface{} `json:"value"`
@@ -47,6 +47,17 @@ type FlagConfig struct {
 	DefaultValue      interface{} `json:"defaultValue"`
 	Rules             []Rule      `json:"rules"`
 	RolloutPercentage int         `json:"rolloutPercentage"`
+	// Variations splits traffic across multiple values by basis-point
+	// weight via bucketBy, for experiments with more than an on/off
+	// rollout. Takes precedence over RolloutPercentage when set.
+	Variations []Variation `json:"variations,omitempty"`
+}
+
+// Variation is one possible value a flag can serve, claiming a contiguous
+// slice of the 10000-basis-point bucket space via Weight.
+type Variation struct {
+	Value  interface{} `json:"value"`
+	Weight int         `json:"weight"`
 }
 
 // FlagsResponse is the API response for fetching flags
@@ -68,8 +79,39 @@ type Config struct {
 	MaxStaleAge time.Duration
 	// HTTPClient allows using a custom HTTP client
 	HTTPClient *http.Client
+	// StreamingMode controls how the client receives flag updates (default: Poll)
+	StreamingMode StreamingMode
+	// BootstrapFile, if set, seeds flags from a local JSON datafile before
+	// the first network call. Initialize() still waits for that first API
+	// fetch; if it fails and no Cache entry loads, Initialize() succeeds and
+	// serves the bootstrapped flags instead of returning the error.
+	BootstrapFile string
+	// Watcher, if set, hot-reloads BootstrapFile on change. Implementations
+	// live in their own subpackages behind this interface so the core
+	// module doesn't have to depend on a filesystem-watching library. See
+	// watch/fsnotify for an example.
+	Watcher FileWatcher
+	// OfflineMode skips polling/streaming entirely and serves flags only
+	// from BootstrapFile.
+	OfflineMode bool
+	// Cache persists the last-known-good flags snapshot so Initialize() can
+	// recover from a transient API outage instead of failing outright.
+	Cache Cache
+	// EventsEnabled turns on evaluation event tracking and batched analytics upload
+	EventsEnabled bool
+	// EventsFlushInterval is how often queued events are uploaded (default: 30s)
+	EventsFlushInterval time.Duration
+	// EventsMaxQueueSize bounds the in-memory event queue; events are dropped once it's full (default: 1000)
+	EventsMaxQueueSize int
 }
 
+const (
+	// DefaultEventsFlushInterval is the default interval between event uploads
+	DefaultEventsFlushInterval = 30 * time.Second
+	// DefaultEventsMaxQueueSize is the default bound on the in-memory event queue
+	DefaultEventsMaxQueueSize = 1000
+)
+
 const DefaultMaxStaleAge = 5 * time.Minute
 
 // ConnectionStatus represents the staleness status of cached flags
@@ -88,26 +130,41 @@ type Client struct {
 	onStale         func(lastUpdatedAt time.Time, age time.Duration)
 	maxStaleAge     time.Duration
 	httpClient      *http.Client
-
-	flags         map[string]FlagConfig
-	mu            sync.RWMutex
-	initialized   bool
-	stopChan      chan struct{}
-	wg            sync.WaitGroup
-	lastUpdatedAt time.Time
-	staleFired    bool
+	streamingMode   StreamingMode
+	bootstrapFile   string
+	watcher         FileWatcher
+	watcherStop     func()
+	offlineMode     bool
+	cache           Cache
+
+	eventsEnabled       bool
+	eventsFlushInterval time.Duration
+	eventsMaxQueueSize  int
+	eventsChan          chan Event
+
+	flags              map[string]FlagConfig
+	mu                 sync.RWMutex
+	initialized        bool
+	stopChan           chan struct{}
+	wg                 sync.WaitGroup
+	lastUpdatedAt      time.Time
+	staleFired         bool
+	streamCancel       context.CancelFunc
+	pollFallbackCancel func()
 }
 
 // NewClient creates a new ToggleTown client
 func NewClient(apiKey string, config *Config) *Client {
 	c := &Client{
-		apiKey:          apiKey,
-		apiURL:          DefaultAPIURL,
-		pollingInterval: DefaultPollingInterval,
-		maxStaleAge:     DefaultMaxStaleAge,
-		httpClient:      http.DefaultClient,
-		flags:           make(map[string]FlagConfig),
-		stopChan:        make(chan struct{}),
+		apiKey:              apiKey,
+		apiURL:              DefaultAPIURL,
+		pollingInterval:     DefaultPollingInterval,
+		maxStaleAge:         DefaultMaxStaleAge,
+		httpClient:          http.DefaultClient,
+		flags:               make(map[string]FlagConfig),
+		stopChan:            make(chan struct{}),
+		eventsFlushInterval: DefaultEventsFlushInterval,
+		eventsMaxQueueSize:  DefaultEventsMaxQueueSize,
 	}
 
 	if config != nil {
@@ -129,7 +186,20 @@ func NewClient(apiKey string, config *Config) *Client {
 		if config.HTTPClient != nil {
 			c.httpClient = config.HTTPClient
 		}
+		c.streamingMode = config.StreamingMode
+		c.bootstrapFile = config.BootstrapFile
+		c.watcher = config.Watcher
+		c.offlineMode = config.OfflineMode
+		c.cache = config.Cache
+		c.eventsEnabled = config.EventsEnabled
+		if config.EventsFlushInterval > 0 {
+			c.eventsFlushInterval = config.EventsFlushInterval
+		}
+		if config.EventsMaxQueueSize > 0 {
+			c.eventsMaxQueueSize = config.EventsMaxQueueSize
+		}
 	}
+	c.eventsChan = make(chan Event, c.eventsMaxQueueSize)
 
 	return c
 }
@@ -140,13 +210,49 @@ func (c *Client) Initialize() error {
 		return nil
 	}
 
-	// Initial fetch - returns error on failure
+	if c.bootstrapFile != "" {
+		if err := c.loadBootstrapFile(); err != nil {
+			return fmt.Errorf("failed to load bootstrap file: %w", err)
+		}
+	}
+
+	if c.offlineMode {
+		c.initialized = true
+		if c.watcher != nil {
+			c.startFileWatch()
+		}
+		if c.eventsEnabled {
+			c.startEventFlusher()
+		}
+		return nil
+	}
+
+	// Initial fetch - on failure, fall back to the cache, then to a
+	// bootstrap file that already seeded usable flags, before giving up
 	if err := c.fetchFlagsInitial(); err != nil {
-		return err
+		if c.cache != nil && c.loadFromCache() {
+			if c.onError != nil {
+				c.onError(err)
+			}
+		} else if c.bootstrapFile == "" {
+			return err
+		} else if c.onError != nil {
+			c.onError(err)
+		}
 	}
 
 	c.initialized = true
-	c.startPolling()
+	if c.watcher != nil {
+		c.startFileWatch()
+	}
+	if c.streamingMode == StreamingModeStream || c.streamingMode == StreamingModeStreamWithPollFallback {
+		c.startStreaming()
+	} else {
+		c.startPolling()
+	}
+	if c.eventsEnabled {
+		c.startEventFlusher()
+	}
 	return nil
 }
 
@@ -155,60 +261,51 @@ func (c *Client) IsInitialized() bool {
 	return c.initialized
 }
 
-// Close stops polling and releases resources
+// Close stops polling/streaming and releases resources
 func (c *Client) Close() {
+	c.mu.Lock()
+	if c.streamCancel != nil {
+		c.streamCancel()
+	}
+	if c.pollFallbackCancel != nil {
+		c.pollFallbackCancel()
+		c.pollFallbackCancel = nil
+	}
+	if c.watcherStop != nil {
+		c.watcherStop()
+		c.watcherStop = nil
+	}
+	c.mu.Unlock()
+
 	if c.stopChan != nil {
 		close(c.stopChan)
 		c.wg.Wait()
 	}
 }
 
-func (c *Client) fetchFlagsInitial() error {
-	req, err := http.NewRequest("GET", c.apiURL+"/api/v1/sdk/flags", nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("X-API-Key", c.apiKey)
+func (c *Client) httpSource() *httpFlagSource {
+	return &httpFlagSource{apiKey: c.apiKey, apiURL: c.apiURL, httpClient: c.httpClient}
+}
 
-	resp, err := c.httpClient.Do(req)
+func (c *Client) fetchFlagsInitial() error {
+	flagsResp, err := c.httpSource().Fetch(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to fetch flags: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var flagsResp FlagsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&flagsResp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return err
 	}
 
+	now := time.Now()
 	c.mu.Lock()
 	c.flags = flagsResp.Flags
-	c.lastUpdatedAt = time.Now()
+	c.lastUpdatedAt = now
 	c.staleFired = false
 	c.mu.Unlock()
+	c.saveToCacheAsync(flagsResp, now)
 
 	return nil
 }
 
 func (c *Client) fetchFlags() {
-	req, err := http.NewRequest("GET", c.apiURL+"/api/v1/sdk/flags", nil)
-	if err != nil {
-		if c.onError != nil {
-			c.onError(err)
-		}
-		c.checkStaleness()
-		return
-	}
-
-	req.Header.Set("X-API-Key", c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
+	flagsResp, err := c.httpSource().Fetch(context.Background())
 	if err != nil {
 		if c.onError != nil {
 			c.onError(err)
@@ -216,31 +313,14 @@ func (c *Client) fetchFlags() {
 		c.checkStaleness()
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		if c.onError != nil {
-			body, _ := io.ReadAll(resp.Body)
-			c.onError(fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body)))
-		}
-		c.checkStaleness()
-		return
-	}
-
-	var flagsResp FlagsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&flagsResp); err != nil {
-		if c.onError != nil {
-			c.onError(err)
-		}
-		c.checkStaleness()
-		return
-	}
 
+	now := time.Now()
 	c.mu.Lock()
 	c.flags = flagsResp.Flags
-	c.lastUpdatedAt = time.Now()
+	c.lastUpdatedAt = now
 	c.staleFired = false
 	c.mu.Unlock()
+	c.saveToCacheAsync(flagsResp, now)
 }
 
 func (c *Client) checkStaleness() {
@@ -326,11 +406,13 @@ func (c *Client) GetBooleanFlag(key string, defaultValue bool, context map[strin
 		return defaultValue
 	}
 
-	value := evaluateFlag(config, context)
+	value, ruleID := evaluateFlagVerbose(config, context, c.onError)
+	result := defaultValue
 	if v, ok := value.(bool); ok {
-		return v
+		result = v
 	}
-	return defaultValue
+	c.recordEvent(key, result, context, ruleID)
+	return result
 }
 
 // GetStringFlag returns a string flag value
@@ -340,11 +422,13 @@ func (c *Client) GetStringFlag(key string, defaultValue string, context map[stri
 		return defaultValue
 	}
 
-	value := evaluateFlag(config, context)
+	value, ruleID := evaluateFlagVerbose(config, context, c.onError)
+	result := defaultValue
 	if v, ok := value.(string); ok {
-		return v
+		result = v
 	}
-	return defaultValue
+	c.recordEvent(key, result, context, ruleID)
+	return result
 }
 
 // GetNumberFlag returns a number flag value
@@ -354,19 +438,20 @@ func (c *Client) GetNumberFlag(key string, defaultValue float64, context map[str
 		return defaultValue
 	}
 
-	value := evaluateFlag(config, context)
+	value, ruleID := evaluateFlagVerbose(config, context, c.onError)
+	result := defaultValue
 	switch v := value.(type) {
 	case float64:
-		return v
+		result = v
 	case float32:
-		return float64(v)
+		result = float64(v)
 	case int:
-		return float64(v)
+		result = float64(v)
 	case int64:
-		return float64(v)
-	default:
-		return defaultValue
+		result = float64(v)
 	}
+	c.recordEvent(key, result, context, ruleID)
+	return result
 }
 
 // GetJSONFlag returns a JSON flag value
@@ -376,11 +461,13 @@ func (c *Client) GetJSONFlag(key string, defaultValue interface{}, context map[s
 		return defaultValue
 	}
 
-	value := evaluateFlag(config, context)
+	value, ruleID := evaluateFlagVerbose(config, context, c.onError)
+	result := defaultValue
 	if value != nil {
-		return value
+		result = value
 	}
-	return defaultValue
+	c.recordEvent(key, result, context, ruleID)
+	return result
 }
 
 // GetAllFlags returns all flag configurations (for debugging)
@@ -394,3 +481,30 @@ func (c *Client) GetAllFlags() map[string]FlagConfig {
 	}
 	return result
 }
+
+// GetFlagConfig returns the raw configuration for key, for integrations
+// that need more than an evaluated value (e.g. to report an OpenFeature
+// Reason).
+func (c *Client) GetFlagConfig(key string) (FlagConfig, bool) {
+	return c.getFlagConfig(key)
+}
+
+// EvaluationDetail is the result of evaluating a flag, including the ID of
+// the targeting rule (if any) that decided it.
+type EvaluationDetail struct {
+	Value  interface{}
+	RuleID string
+}
+
+// Evaluate returns the full evaluation detail for key, or ok=false if the
+// flag isn't known to the client.
+func (c *Client) Evaluate(key string, context map[string]interface{}) (EvaluationDetail, bool) {
+	config, ok := c.getFlagConfig(key)
+	if !ok {
+		return EvaluationDetail{}, false
+	}
+
+	value, ruleID := evaluateFlagVerbose(config, context, c.onError)
+	c.recordEvent(key, value, context, ruleID)
+	return EvaluationDetail{Value: value, RuleID: ruleID}, true
+}
@@ -2,9 +2,13 @@ package toggletown
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // hashString hashes a string to a number between 0-99 for rollout bucketing.
@@ -27,8 +31,45 @@ func isInRollout(userID, flagKey string, percentage int) bool {
 	return bucket < percentage
 }
 
+// bucketBy deterministically buckets (salt, key, userID) into [0, 10000)
+// for basis-point precision. This algorithm must match Node.js and browser
+// SDKs byte-for-byte, so it and its inputs can't change without updating
+// them too.
+func bucketBy(salt, key, userID string) uint32 {
+	h := sha256.Sum256([]byte(salt + ":" + key + ":" + userID))
+	num := binary.BigEndian.Uint64(h[:8])
+	return uint32(num % 10000)
+}
+
+// selectVariation picks a Variation for userID by weighted basis-point
+// bucket, returning ok=false if userID is empty or the weights don't cover
+// the bucket it lands in.
+func selectVariation(config FlagConfig, userID string) (interface{}, bool) {
+	if userID == "" {
+		return nil, false
+	}
+
+	bucket := bucketBy(config.Key, "variations", userID)
+	var cumulative uint32
+	for _, v := range config.Variations {
+		cumulative += uint32(v.Weight)
+		if bucket < cumulative {
+			return v.Value, true
+		}
+	}
+	return nil, false
+}
+
 // matchesRule checks if context matches a targeting rule
 func matchesRule(rule Rule, context map[string]interface{}) bool {
+	matched, _ := matchesRuleWithError(rule, context)
+	return matched
+}
+
+// matchesRuleWithError is like matchesRule but also surfaces operator
+// errors (a bad regex or an unparseable semver/timestamp) so callers can
+// report them via OnError. An error always comes with matched=false.
+func matchesRuleWithError(rule Rule, context map[string]interface{}) (bool, error) {
 	attribute := rule.Attribute
 	operator := rule.Operator
 	ruleValue := rule.Value
@@ -40,65 +81,240 @@ func matchesRule(rule Rule, context map[string]interface{}) bool {
 		if attrs, ok := context["attributes"].(map[string]interface{}); ok {
 			attrValue, ok = attrs[attribute]
 			if !ok {
-				return false
+				return false, nil
 			}
 		} else {
-			return false
+			return false, nil
 		}
 	}
 
 	switch operator {
 	case "equals":
-		return fmt.Sprintf("%v", attrValue) == fmt.Sprintf("%v", ruleValue)
+		return fmt.Sprintf("%v", attrValue) == fmt.Sprintf("%v", ruleValue), nil
 	case "not_equals":
-		return fmt.Sprintf("%v", attrValue) != fmt.Sprintf("%v", ruleValue)
+		return fmt.Sprintf("%v", attrValue) != fmt.Sprintf("%v", ruleValue), nil
 	case "contains":
 		attrStr, ok1 := attrValue.(string)
 		ruleStr, ok2 := ruleValue.(string)
 		if ok1 && ok2 {
-			return strings.Contains(attrStr, ruleStr)
+			return strings.Contains(attrStr, ruleStr), nil
 		}
-		return false
+		return false, nil
 	case "not_contains":
 		attrStr, ok1 := attrValue.(string)
 		ruleStr, ok2 := ruleValue.(string)
 		if ok1 && ok2 {
-			return !strings.Contains(attrStr, ruleStr)
+			return !strings.Contains(attrStr, ruleStr), nil
 		}
-		return false
+		return false, nil
+	case "starts_with":
+		attrStr, ok1 := attrValue.(string)
+		ruleStr, ok2 := ruleValue.(string)
+		if ok1 && ok2 {
+			return strings.HasPrefix(attrStr, ruleStr), nil
+		}
+		return false, nil
+	case "ends_with":
+		attrStr, ok1 := attrValue.(string)
+		ruleStr, ok2 := ruleValue.(string)
+		if ok1 && ok2 {
+			return strings.HasSuffix(attrStr, ruleStr), nil
+		}
+		return false, nil
 	case "gt":
-		attrFloat := toFloat(attrValue)
-		ruleFloat := toFloat(ruleValue)
-		return attrFloat > ruleFloat
+		return toFloat(attrValue) > toFloat(ruleValue), nil
 	case "lt":
-		attrFloat := toFloat(attrValue)
-		ruleFloat := toFloat(ruleValue)
-		return attrFloat < ruleFloat
+		return toFloat(attrValue) < toFloat(ruleValue), nil
 	case "in":
-		if list, ok := ruleValue.([]interface{}); ok {
-			for _, item := range list {
-				if fmt.Sprintf("%v", attrValue) == fmt.Sprintf("%v", item) {
-					return true
-				}
-			}
-		}
-		return false
+		return matchesSet(attrValue, ruleValue), nil
 	case "not_in":
-		if list, ok := ruleValue.([]interface{}); ok {
-			for _, item := range list {
-				if fmt.Sprintf("%v", attrValue) == fmt.Sprintf("%v", item) {
-					return false
-				}
-			}
+		return !matchesSet(attrValue, ruleValue), nil
+	case "matches_regex":
+		return matchesRegex(attrValue, ruleValue)
+	case "not_matches_regex":
+		matched, err := matchesRegex(attrValue, ruleValue)
+		if err != nil {
+			return false, err
 		}
-		return true
+		return !matched, nil
+	case "semver_gt":
+		return compareSemver(attrValue, ruleValue, func(cmp int) bool { return cmp > 0 })
+	case "semver_lt":
+		return compareSemver(attrValue, ruleValue, func(cmp int) bool { return cmp < 0 })
+	case "semver_eq":
+		return compareSemver(attrValue, ruleValue, func(cmp int) bool { return cmp == 0 })
+	case "before":
+		return compareTime(attrValue, ruleValue, func(diff time.Duration) bool { return diff < 0 })
+	case "after":
+		return compareTime(attrValue, ruleValue, func(diff time.Duration) bool { return diff > 0 })
 	case "always":
-		return true
+		return true, nil
 	}
 
+	return false, nil
+}
+
+// matchesSet reports whether attrValue equals any element of ruleValue
+// (expected to be a []interface{}), comparing numerically when both sides
+// are numeric so large or fractional values don't lose fidelity through
+// string formatting.
+func matchesSet(attrValue, ruleValue interface{}) bool {
+	list, ok := ruleValue.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if valuesEqual(attrValue, item) {
+			return true
+		}
+	}
 	return false
 }
 
+func valuesEqual(a, b interface{}) bool {
+	if an, aok := numericValue(a); aok {
+		if bn, bok := numericValue(b); bok {
+			return an == bn
+		}
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+func numericValue(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	}
+	return 0, false
+}
+
+// regexCache holds compiled *regexp.Regexp keyed by pattern string, so
+// matches_regex/not_matches_regex don't recompile on every evaluation.
+var regexCache sync.Map
+
+func compiledRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+func matchesRegex(attrValue, ruleValue interface{}) (bool, error) {
+	attrStr, ok1 := attrValue.(string)
+	patternStr, ok2 := ruleValue.(string)
+	if !ok1 || !ok2 {
+		return false, nil
+	}
+	re, err := compiledRegex(patternStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid regex pattern %q: %w", patternStr, err)
+	}
+	return re.MatchString(attrStr), nil
+}
+
+// semver is a minimal MAJOR.MINOR.PATCH[-pre] parser, sufficient for
+// semver_gt/semver_lt/semver_eq targeting rules.
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+func parseSemver(s string) (semver, error) {
+	core := s
+	pre := ""
+	if idx := strings.IndexByte(s, '-'); idx != -1 {
+		core, pre = s[:idx], s[idx+1:]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return semver{}, fmt.Errorf("invalid semver %q", s)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return semver{}, fmt.Errorf("invalid semver %q: %w", s, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return semver{}, fmt.Errorf("invalid semver %q: %w", s, err)
+	}
+	patch, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return semver{}, fmt.Errorf("invalid semver %q: %w", s, err)
+	}
+	return semver{major: major, minor: minor, patch: patch, pre: pre}, nil
+}
+
+// compareSemverValues returns <0, 0, >0 as a < b, a == b, a > b. A
+// pre-release version has lower precedence than the associated normal
+// version, matching semver's precedence rules.
+func compareSemverValues(a, b semver) int {
+	if a.major != b.major {
+		return a.major - b.major
+	}
+	if a.minor != b.minor {
+		return a.minor - b.minor
+	}
+	if a.patch != b.patch {
+		return a.patch - b.patch
+	}
+	switch {
+	case a.pre == "" && b.pre == "":
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	default:
+		return strings.Compare(a.pre, b.pre)
+	}
+}
+
+func compareSemver(attrValue, ruleValue interface{}, satisfies func(cmp int) bool) (bool, error) {
+	attrStr, ok1 := attrValue.(string)
+	ruleStr, ok2 := ruleValue.(string)
+	if !ok1 || !ok2 {
+		return false, nil
+	}
+	a, err := parseSemver(attrStr)
+	if err != nil {
+		return false, err
+	}
+	b, err := parseSemver(ruleStr)
+	if err != nil {
+		return false, err
+	}
+	return satisfies(compareSemverValues(a, b)), nil
+}
+
+func compareTime(attrValue, ruleValue interface{}, satisfies func(diff time.Duration) bool) (bool, error) {
+	attrStr, ok1 := attrValue.(string)
+	ruleStr, ok2 := ruleValue.(string)
+	if !ok1 || !ok2 {
+		return false, nil
+	}
+	attrTime, err := time.Parse(time.RFC3339, attrStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid timestamp %q: %w", attrStr, err)
+	}
+	ruleTime, err := time.Parse(time.RFC3339, ruleStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid timestamp %q: %w", ruleStr, err)
+	}
+	return satisfies(attrTime.Sub(ruleTime)), nil
+}
+
 // toFloat converts various types to float64
 func toFloat(v interface{}) float64 {
 	switch val := v.(type) {
@@ -134,23 +350,47 @@ func getOffValue(flagType string) interface{} {
 	}
 }
 
+// contextUserID extracts the user identifier from a context, checking both
+// the snake_case and camelCase conventions accepted elsewhere.
+func contextUserID(context map[string]interface{}) string {
+	if id, ok := context["user_id"].(string); ok {
+		return id
+	}
+	if id, ok := context["userId"].(string); ok {
+		return id
+	}
+	return ""
+}
+
 // evaluateFlag evaluates a flag for a given context
 func evaluateFlag(config FlagConfig, context map[string]interface{}) interface{} {
+	value, _ := evaluateFlagVerbose(config, context, nil)
+	return value
+}
+
+// evaluateFlagVerbose evaluates a flag like evaluateFlag but also returns
+// the ID of the targeting rule that decided the result, if any, so callers
+// (e.g. event tracking) can attribute the evaluation to a rule. onError, if
+// non-nil, is called with any operator error (bad regex, unparseable
+// semver/timestamp) encountered while matching rules; the rule is then
+// treated as not matching.
+func evaluateFlagVerbose(config FlagConfig, context map[string]interface{}, onError func(error)) (interface{}, string) {
 	if !config.Enabled {
-		return config.DefaultValue
+		return config.DefaultValue, ""
 	}
 
-	// Get user_id for rollout
-	userID := ""
-	if id, ok := context["user_id"].(string); ok {
-		userID = id
-	} else if id, ok := context["userId"].(string); ok {
-		userID = id
-	}
+	userID := contextUserID(context)
 
 	// Check targeting rules in order
 	for _, rule := range config.Rules {
-		if !matchesRule(rule, context) {
+		matched, err := matchesRuleWithError(rule, context)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			continue
+		}
+		if !matched {
 			continue
 		}
 
@@ -162,18 +402,26 @@ func evaluateFlag(config FlagConfig, context map[string]interface{}) interface{}
 		}
 
 		if rule.RollValue != nil {
-			return rule.RollValue
+			return rule.RollValue, rule.ID
+		}
+		return config.DefaultValue, rule.ID
+	}
+
+	// No rules matched - split by Variations if configured, else fall back
+	// to the legacy global percentage rollout
+	if len(config.Variations) > 0 {
+		if value, ok := selectVariation(config, userID); ok {
+			return value, ""
 		}
-		return config.DefaultValue
+		return getOffValue(config.Type), ""
 	}
 
-	// No rules matched - check global percentage rollout
 	if config.RolloutPercentage > 0 && userID != "" {
 		if isInRollout(userID, config.Key, config.RolloutPercentage) {
-			return config.DefaultValue
+			return config.DefaultValue, ""
 		}
-		return getOffValue(config.Type)
+		return getOffValue(config.Type), ""
 	}
 
-	return config.DefaultValue
+	return config.DefaultValue, ""
 }
@@ -37,6 +37,49 @@ func TestIsInRollout(t *testing.T) {
 	})
 }
 
+func TestBucketBy(t *testing.T) {
+	t.Run("deterministic", func(t *testing.T) {
+		assert.Equal(t, bucketBy("salt", "flag", "user-123"), bucketBy("salt", "flag", "user-123"))
+	})
+
+	t.Run("range 0-9999", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			bucket := bucketBy("salt", "flag", fmt.Sprintf("user-%d", i))
+			assert.Less(t, bucket, uint32(10000))
+		}
+	})
+}
+
+func TestSelectVariation(t *testing.T) {
+	config := FlagConfig{
+		Key: "checkout-redesign",
+		Variations: []Variation{
+			{Value: "control", Weight: 5000},
+			{Value: "treatment", Weight: 5000},
+		},
+	}
+
+	t.Run("no user id", func(t *testing.T) {
+		_, ok := selectVariation(config, "")
+		assert.False(t, ok)
+	})
+
+	t.Run("deterministic per user", func(t *testing.T) {
+		value1, ok1 := selectVariation(config, "user-123")
+		value2, ok2 := selectVariation(config, "user-123")
+		assert.True(t, ok1)
+		assert.True(t, ok2)
+		assert.Equal(t, value1, value2)
+	})
+
+	t.Run("covers the full bucket space", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			_, ok := selectVariation(config, fmt.Sprintf("user-%d", i))
+			assert.True(t, ok)
+		}
+	})
+}
+
 func TestMatchesRule(t *testing.T) {
 	t.Run("equals", func(t *testing.T) {
 		rule := Rule{Attribute: "plan", Operator: "equals", Value: "pro"}
@@ -67,6 +110,51 @@ func TestMatchesRule(t *testing.T) {
 		assert.True(t, matchesRule(rule, map[string]interface{}{"country": "US"}))
 		assert.False(t, matchesRule(rule, map[string]interface{}{"country": "DE"}))
 	})
+
+	t.Run("in list preserves numeric fidelity", func(t *testing.T) {
+		rule := Rule{Attribute: "plan_id", Operator: "in", Value: []interface{}{float64(1), float64(2)}}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"plan_id": 2}))
+		assert.False(t, matchesRule(rule, map[string]interface{}{"plan_id": 3}))
+	})
+
+	t.Run("starts_with / ends_with", func(t *testing.T) {
+		rule := Rule{Attribute: "email", Operator: "starts_with", Value: "admin"}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"email": "admin@company.com"}))
+		assert.False(t, matchesRule(rule, map[string]interface{}{"email": "user@company.com"}))
+
+		rule = Rule{Attribute: "email", Operator: "ends_with", Value: "@company.com"}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"email": "user@company.com"}))
+		assert.False(t, matchesRule(rule, map[string]interface{}{"email": "user@other.com"}))
+	})
+
+	t.Run("matches_regex", func(t *testing.T) {
+		rule := Rule{Attribute: "email", Operator: "matches_regex", Value: `^[a-z]+@company\.com$`}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"email": "user@company.com"}))
+		assert.False(t, matchesRule(rule, map[string]interface{}{"email": "User@company.com"}))
+
+		rule = Rule{Attribute: "email", Operator: "not_matches_regex", Value: `^[a-z]+@company\.com$`}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"email": "User@company.com"}))
+	})
+
+	t.Run("invalid regex does not match", func(t *testing.T) {
+		rule := Rule{Attribute: "email", Operator: "matches_regex", Value: "("}
+		assert.False(t, matchesRule(rule, map[string]interface{}{"email": "user@company.com"}))
+	})
+
+	t.Run("semver comparisons", func(t *testing.T) {
+		rule := Rule{Attribute: "app_version", Operator: "semver_gt", Value: "1.2.0"}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"app_version": "1.3.0"}))
+		assert.False(t, matchesRule(rule, map[string]interface{}{"app_version": "1.1.0"}))
+
+		rule = Rule{Attribute: "app_version", Operator: "semver_eq", Value: "2.0.0-beta"}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"app_version": "2.0.0-beta"}))
+	})
+
+	t.Run("before / after", func(t *testing.T) {
+		rule := Rule{Attribute: "signup_date", Operator: "after", Value: "2024-01-01T00:00:00Z"}
+		assert.True(t, matchesRule(rule, map[string]interface{}{"signup_date": "2024-06-01T00:00:00Z"}))
+		assert.False(t, matchesRule(rule, map[string]interface{}{"signup_date": "2023-06-01T00:00:00Z"}))
+	})
 }
 
 func TestEvaluateFlag(t *testing.T) {
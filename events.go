@@ -0,0 +1,165 @@
+package toggletown
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Event records a single flag evaluation for analytics and experiment
+// analysis.
+type Event struct {
+	FlagKey     string      `json:"flag_key"`
+	Variant     interface{} `json:"variant"`
+	UserID      string      `json:"user_id"`
+	ContextHash string      `json:"context_hash"`
+	Timestamp   time.Time   `json:"timestamp"`
+	RuleID      string      `json:"rule_id,omitempty"`
+}
+
+// recordEvent queues an evaluation event, if events are enabled. The queue
+// is bounded; once full, events are dropped and reported via OnError rather
+// than blocking the caller.
+func (c *Client) recordEvent(flagKey string, variant interface{}, context map[string]interface{}, ruleID string) {
+	if !c.eventsEnabled {
+		return
+	}
+
+	event := Event{
+		FlagKey:     flagKey,
+		Variant:     variant,
+		UserID:      contextUserID(context),
+		ContextHash: hashContext(context),
+		Timestamp:   time.Now(),
+		RuleID:      ruleID,
+	}
+
+	select {
+	case c.eventsChan <- event:
+	default:
+		if c.onError != nil {
+			c.onError(fmt.Errorf("event queue full, dropping evaluation event for %q", flagKey))
+		}
+	}
+}
+
+// hashContext produces a deterministic hash of a context map so events can
+// be grouped by distinct targeting context without uploading raw attributes.
+func hashContext(context map[string]interface{}) string {
+	keys := make([]string, 0, len(context))
+	for k := range context {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%v;", k, context[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// startEventFlusher drains queued events in batches, uploading them on a
+// fixed interval or when the queue crosses EventsMaxQueueSize.
+func (c *Client) startEventFlusher() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.eventsFlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]Event, 0, c.eventsMaxQueueSize)
+		for {
+			select {
+			case event := <-c.eventsChan:
+				batch = append(batch, event)
+				if len(batch) >= c.eventsMaxQueueSize {
+					batch = c.flushEvents(batch)
+				}
+			case <-ticker.C:
+				batch = c.flushEvents(batch)
+			case <-c.stopChan:
+				c.flushEvents(batch)
+				return
+			}
+		}
+	}()
+}
+
+func (c *Client) flushEvents(batch []Event) []Event {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := c.uploadEvents(context.Background(), batch); err != nil && c.onError != nil {
+		c.onError(err)
+	}
+	return batch[:0]
+}
+
+// Flush uploads any queued evaluation events immediately instead of waiting
+// for the next scheduled flush.
+func (c *Client) Flush(ctx context.Context) error {
+	if !c.eventsEnabled {
+		return nil
+	}
+
+	batch := make([]Event, 0, c.eventsMaxQueueSize)
+	for {
+		select {
+		case event := <-c.eventsChan:
+			batch = append(batch, event)
+		default:
+			if len(batch) == 0 {
+				return nil
+			}
+			return c.uploadEvents(ctx, batch)
+		}
+	}
+}
+
+// uploadEvents gzip-encodes a batch of events and POSTs them to the events
+// endpoint.
+func (c *Client) uploadEvents(ctx context.Context, events []Event) error {
+	payload, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(payload); err != nil {
+		gz.Close()
+		return fmt.Errorf("failed to gzip events: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to gzip events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL+"/api/v1/sdk/events", &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create events request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("events API returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package toggletown
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashContext(t *testing.T) {
+	t.Run("deterministic regardless of map order", func(t *testing.T) {
+		a := hashContext(map[string]interface{}{"user_id": "123", "plan": "pro"})
+		b := hashContext(map[string]interface{}{"plan": "pro", "user_id": "123"})
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("differs for different contexts", func(t *testing.T) {
+		a := hashContext(map[string]interface{}{"plan": "pro"})
+		b := hashContext(map[string]interface{}{"plan": "free"})
+		assert.NotEqual(t, a, b)
+	})
+}
+
+func TestRecordEvent(t *testing.T) {
+	t.Run("no-op when events disabled", func(t *testing.T) {
+		c := NewClient("key", nil)
+		c.recordEvent("flag", true, map[string]interface{}{"user_id": "u1"}, "")
+		assert.Empty(t, c.eventsChan)
+	})
+
+	t.Run("queues an event when enabled", func(t *testing.T) {
+		c := NewClient("key", &Config{EventsEnabled: true})
+		c.recordEvent("flag", true, map[string]interface{}{"user_id": "u1"}, "rule-1")
+		assert.Len(t, c.eventsChan, 1)
+		event := <-c.eventsChan
+		assert.Equal(t, "flag", event.FlagKey)
+		assert.Equal(t, "u1", event.UserID)
+		assert.Equal(t, "rule-1", event.RuleID)
+	})
+
+	t.Run("drops and reports when the queue is full", func(t *testing.T) {
+		var reported error
+		c := NewClient("key", &Config{
+			EventsEnabled:      true,
+			EventsMaxQueueSize: 1,
+			OnError:            func(err error) { reported = err },
+		})
+		c.recordEvent("flag", true, map[string]interface{}{}, "")
+		c.recordEvent("flag", true, map[string]interface{}{}, "")
+		assert.Len(t, c.eventsChan, 1)
+		assert.Error(t, reported)
+	})
+}
+
+func TestUploadEvents(t *testing.T) {
+	t.Run("gzip-encodes the batch and posts it", func(t *testing.T) {
+		var gotEvents []Event
+		var gotEncoding, gotAPIKey string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotEncoding = r.Header.Get("Content-Encoding")
+			gotAPIKey = r.Header.Get("X-API-Key")
+
+			gz, err := gzip.NewReader(r.Body)
+			assert.NoError(t, err)
+			data, err := io.ReadAll(gz)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(data, &gotEvents))
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c := NewClient("test-key", &Config{APIURL: server.URL})
+		events := []Event{{FlagKey: "flag", UserID: "u1", Timestamp: time.Now()}}
+
+		err := c.uploadEvents(context.Background(), events)
+		assert.NoError(t, err)
+		assert.Equal(t, "gzip", gotEncoding)
+		assert.Equal(t, "test-key", gotAPIKey)
+		assert.Len(t, gotEvents, 1)
+		assert.Equal(t, "flag", gotEvents[0].FlagKey)
+	})
+
+	t.Run("returns an error on a non-2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL})
+		err := c.uploadEvents(context.Background(), []Event{{FlagKey: "flag"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestFlush(t *testing.T) {
+	t.Run("no-op when events disabled", func(t *testing.T) {
+		c := NewClient("key", nil)
+		assert.NoError(t, c.Flush(context.Background()))
+	})
+
+	t.Run("uploads queued events immediately", func(t *testing.T) {
+		uploaded := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			uploaded = true
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL, EventsEnabled: true})
+		c.recordEvent("flag", true, map[string]interface{}{"user_id": "u1"}, "")
+
+		assert.NoError(t, c.Flush(context.Background()))
+		assert.True(t, uploaded)
+		assert.Empty(t, c.eventsChan)
+	})
+}
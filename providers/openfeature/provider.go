@@ -0,0 +1,137 @@
+// Package openfeature implements the OpenFeature FeatureProvider interface
+// backed by a toggletown.Client, so applications can adopt the
+// vendor-neutral OpenFeature API without rewriting call sites.
+package openfeature
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	toggletown "github.com/toggletown/sdk-go"
+)
+
+// Provider adapts a *toggletown.Client to the OpenFeature FeatureProvider
+// interface.
+type Provider struct {
+	client *toggletown.Client
+}
+
+// NewProvider returns an OpenFeature provider backed by client.
+func NewProvider(client *toggletown.Client) *Provider {
+	return &Provider{client: client}
+}
+
+// Metadata identifies this provider to OpenFeature.
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: "toggletown"}
+}
+
+// Hooks returns no provider-level hooks.
+func (p *Provider) Hooks() []openfeature.Hook {
+	return nil
+}
+
+// BooleanEvaluation evaluates a boolean flag.
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	detail, resolution := p.evaluate(flag, evalCtx)
+	value, ok := detail.Value.(bool)
+	if !ok {
+		value = defaultValue
+	}
+	return openfeature.BoolResolutionDetail{Value: value, ProviderResolutionDetail: resolution}
+}
+
+// StringEvaluation evaluates a string flag.
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	detail, resolution := p.evaluate(flag, evalCtx)
+	value, ok := detail.Value.(string)
+	if !ok {
+		value = defaultValue
+	}
+	return openfeature.StringResolutionDetail{Value: value, ProviderResolutionDetail: resolution}
+}
+
+// FloatEvaluation evaluates a number flag as a float64.
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	detail, resolution := p.evaluate(flag, evalCtx)
+	value := defaultValue
+	switch v := detail.Value.(type) {
+	case float64:
+		value = v
+	case float32:
+		value = float64(v)
+	case int:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	}
+	return openfeature.FloatResolutionDetail{Value: value, ProviderResolutionDetail: resolution}
+}
+
+// IntEvaluation evaluates a number flag as an int64.
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	detail, resolution := p.evaluate(flag, evalCtx)
+	value := defaultValue
+	switch v := detail.Value.(type) {
+	case int64:
+		value = v
+	case int:
+		value = int64(v)
+	case float64:
+		value = int64(v)
+	case float32:
+		value = int64(v)
+	}
+	return openfeature.IntResolutionDetail{Value: value, ProviderResolutionDetail: resolution}
+}
+
+// ObjectEvaluation evaluates a JSON flag.
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	detail, resolution := p.evaluate(flag, evalCtx)
+	value := defaultValue
+	if detail.Value != nil {
+		value = detail.Value
+	}
+	return openfeature.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: resolution}
+}
+
+// evaluate runs the underlying evaluation and derives the OpenFeature
+// resolution detail (Reason, Variant, and any error) from it.
+func (p *Provider) evaluate(flag string, evalCtx openfeature.FlattenedContext) (toggletown.EvaluationDetail, openfeature.ProviderResolutionDetail) {
+	detail, ok := p.client.Evaluate(flag, toContext(evalCtx))
+	if !ok {
+		return toggletown.EvaluationDetail{}, openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %q not found", flag)),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+
+	config, _ := p.client.GetFlagConfig(flag)
+	switch {
+	case !config.Enabled:
+		return detail, openfeature.ProviderResolutionDetail{Reason: openfeature.DisabledReason}
+	case detail.RuleID != "":
+		return detail, openfeature.ProviderResolutionDetail{Reason: openfeature.TargetingMatchReason, Variant: detail.RuleID}
+	case len(config.Variations) > 0:
+		return detail, openfeature.ProviderResolutionDetail{Reason: openfeature.SplitReason}
+	default:
+		return detail, openfeature.ProviderResolutionDetail{Reason: openfeature.DefaultReason}
+	}
+}
+
+// toContext translates an OpenFeature EvaluationContext (already flattened)
+// into ToggleTown's map[string]interface{} context, mapping TargetingKey to
+// user_id.
+func toContext(evalCtx openfeature.FlattenedContext) map[string]interface{} {
+	context := make(map[string]interface{}, len(evalCtx))
+	for k, v := range evalCtx {
+		context[k] = v
+	}
+	if targetingKey, ok := context[openfeature.TargetingKey]; ok {
+		context["user_id"] = targetingKey
+		delete(context, openfeature.TargetingKey)
+	}
+	return context
+}
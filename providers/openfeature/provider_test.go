@@ -0,0 +1,100 @@
+package openfeature
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+
+	toggletown "github.com/toggletown/sdk-go"
+)
+
+func writeBootstrapFile(t *testing.T, data interface{}) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "flags.json")
+	encoded, err := json.Marshal(data)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, encoded, 0o644))
+	return path
+}
+
+func TestBooleanEvaluationFlagNotFound(t *testing.T) {
+	client := toggletown.NewClient("key", &toggletown.Config{OfflineMode: true})
+	assert.NoError(t, client.Initialize())
+	defer client.Close()
+
+	p := NewProvider(client)
+	detail := p.BooleanEvaluation(context.Background(), "missing-flag", true, openfeature.FlattenedContext{})
+
+	assert.True(t, detail.Value)
+	assert.Equal(t, openfeature.ErrorReason, detail.Reason)
+	assert.NotNil(t, detail.ResolutionError)
+}
+
+func TestToContext(t *testing.T) {
+	t.Run("maps TargetingKey to user_id", func(t *testing.T) {
+		evalCtx := openfeature.FlattenedContext{
+			openfeature.TargetingKey: "user-123",
+			"plan":                   "pro",
+		}
+		ctx := toContext(evalCtx)
+
+		assert.Equal(t, "user-123", ctx["user_id"])
+		assert.Equal(t, "pro", ctx["plan"])
+		assert.NotContains(t, ctx, openfeature.TargetingKey)
+	})
+
+	t.Run("passes through a context with no TargetingKey", func(t *testing.T) {
+		ctx := toContext(openfeature.FlattenedContext{"plan": "free"})
+		assert.Equal(t, "free", ctx["plan"])
+		assert.NotContains(t, ctx, "user_id")
+	})
+}
+
+func TestEvaluateReasons(t *testing.T) {
+	bootstrap := map[string]interface{}{
+		"flags": map[string]interface{}{
+			"disabled-flag": map[string]interface{}{
+				"key": "disabled-flag", "type": "BOOLEAN", "enabled": false, "defaultValue": false,
+			},
+			"targeted-flag": map[string]interface{}{
+				"key": "targeted-flag", "type": "BOOLEAN", "enabled": true, "defaultValue": false,
+				"rules": []interface{}{
+					map[string]interface{}{"id": "rule-1", "attribute": "plan", "operator": "equals", "value": "pro", "rollValue": true},
+				},
+			},
+			"default-flag": map[string]interface{}{
+				"key": "default-flag", "type": "BOOLEAN", "enabled": true, "defaultValue": true,
+				"rolloutPercentage": 0, "rules": []interface{}{},
+			},
+		},
+	}
+	path := writeBootstrapFile(t, bootstrap)
+
+	client := toggletown.NewClient("key", &toggletown.Config{OfflineMode: true, BootstrapFile: path})
+	assert.NoError(t, client.Initialize())
+	defer client.Close()
+
+	p := NewProvider(client)
+
+	t.Run("disabled flag reports DisabledReason", func(t *testing.T) {
+		detail := p.BooleanEvaluation(context.Background(), "disabled-flag", false, openfeature.FlattenedContext{})
+		assert.Equal(t, openfeature.DisabledReason, detail.Reason)
+	})
+
+	t.Run("rule match reports TargetingMatchReason with the rule ID as variant", func(t *testing.T) {
+		detail := p.BooleanEvaluation(context.Background(), "targeted-flag", false, openfeature.FlattenedContext{"plan": "pro"})
+		assert.True(t, detail.Value)
+		assert.Equal(t, openfeature.TargetingMatchReason, detail.Reason)
+		assert.Equal(t, "rule-1", detail.Variant)
+	})
+
+	t.Run("no rule match falls back to DefaultReason", func(t *testing.T) {
+		detail := p.BooleanEvaluation(context.Background(), "default-flag", false, openfeature.FlattenedContext{})
+		assert.Equal(t, openfeature.DefaultReason, detail.Reason)
+	})
+}
@@ -0,0 +1,122 @@
+package toggletown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// FlagSource fetches a full FlagsResponse snapshot. It's the extension point
+// for where a Client gets its flags: httpFlagSource is the default used for
+// the initial fetch and for polling, and FileSource serves a local JSON
+// datafile instead.
+type FlagSource interface {
+	Fetch(ctx context.Context) (FlagsResponse, error)
+}
+
+// FileWatcher watches path for changes and calls onChange on each one,
+// reporting any watch errors via onError. It returns a stop function that
+// tears down the watch. Implementations live in their own subpackages
+// behind this interface so the core module doesn't have to depend on a
+// filesystem-watching library. See watch/fsnotify for an example.
+type FileWatcher interface {
+	Watch(path string, onChange func(), onError func(error)) (stop func(), err error)
+}
+
+// httpFlagSource fetches flags from the ToggleTown API. It backs the
+// client's initial fetch and its polling, including the polling fallback in
+// StreamingModeStreamWithPollFallback; the stream itself opens its own
+// connection in connectStream.
+type httpFlagSource struct {
+	apiKey     string
+	apiURL     string
+	httpClient *http.Client
+}
+
+func (s *httpFlagSource) Fetch(ctx context.Context) (FlagsResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.apiURL+"/api/v1/sdk/flags", nil)
+	if err != nil {
+		return FlagsResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-API-Key", s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return FlagsResponse{}, fmt.Errorf("failed to fetch flags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return FlagsResponse{}, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var flagsResp FlagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&flagsResp); err != nil {
+		return FlagsResponse{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return flagsResp, nil
+}
+
+// FileSource reads a FlagsResponse from a JSON datafile on disk. It's
+// useful for offline and CI environments where hitting the real API isn't
+// possible or desirable, and for deterministic tests.
+type FileSource struct {
+	// Path is the location of the JSON datafile.
+	Path string
+}
+
+// Fetch reads and parses the datafile at Path. ctx is accepted to satisfy
+// FlagSource but isn't otherwise used.
+func (s *FileSource) Fetch(ctx context.Context) (FlagsResponse, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return FlagsResponse{}, fmt.Errorf("failed to read datafile: %w", err)
+	}
+
+	var flagsResp FlagsResponse
+	if err := json.Unmarshal(data, &flagsResp); err != nil {
+		return FlagsResponse{}, fmt.Errorf("failed to parse datafile: %w", err)
+	}
+	return flagsResp, nil
+}
+
+// loadBootstrapFile reads bootstrapFile and applies it to the cached flags.
+func (c *Client) loadBootstrapFile() error {
+	flagsResp, err := (&FileSource{Path: c.bootstrapFile}).Fetch(context.Background())
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.flags = flagsResp.Flags
+	c.lastUpdatedAt = time.Now()
+	c.staleFired = false
+	c.mu.Unlock()
+	return nil
+}
+
+// startFileWatch watches bootstrapFile for changes and reloads it on write,
+// for hot reload in OfflineMode and local development.
+func (c *Client) startFileWatch() {
+	stop, err := c.watcher.Watch(c.bootstrapFile, func() {
+		if err := c.loadBootstrapFile(); err != nil && c.onError != nil {
+			c.onError(err)
+		}
+	}, c.onError)
+	if err != nil {
+		if c.onError != nil {
+			c.onError(fmt.Errorf("failed to start file watcher: %w", err))
+		}
+		return
+	}
+
+	c.mu.Lock()
+	c.watcherStop = stop
+	c.mu.Unlock()
+}
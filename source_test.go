@@ -0,0 +1,114 @@
+package toggletown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSourceFetch(t *testing.T) {
+	t.Run("reads and parses a datafile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "flags.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"flags":{"a":{"key":"a","enabled":true}}}`), 0o644))
+
+		resp, err := (&FileSource{Path: path}).Fetch(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, resp.Flags["a"].Enabled)
+	})
+
+	t.Run("missing file is an error", func(t *testing.T) {
+		_, err := (&FileSource{Path: filepath.Join(t.TempDir(), "missing.json")}).Fetch(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid JSON is an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "flags.json")
+		assert.NoError(t, os.WriteFile(path, []byte("not json"), 0o644))
+
+		_, err := (&FileSource{Path: path}).Fetch(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadBootstrapFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"flags":{"a":{"key":"a","enabled":true}}}`), 0o644))
+
+	c := NewClient("key", &Config{BootstrapFile: path})
+	assert.NoError(t, c.loadBootstrapFile())
+
+	config, ok := c.getFlagConfig("a")
+	assert.True(t, ok)
+	assert.True(t, config.Enabled)
+	assert.False(t, c.GetLastUpdatedAt().IsZero())
+}
+
+// fakeWatcher is a test double for FileWatcher that lets the test trigger
+// onChange directly instead of depending on real filesystem events.
+type fakeWatcher struct {
+	stopped bool
+}
+
+func (w *fakeWatcher) Watch(path string, onChange func(), onError func(error)) (func(), error) {
+	onChange()
+	return func() { w.stopped = true }, nil
+}
+
+func TestStartFileWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"flags":{"a":{"key":"a","enabled":true}}}`), 0o644))
+
+	watcher := &fakeWatcher{}
+	c := NewClient("key", &Config{BootstrapFile: path, Watcher: watcher})
+
+	c.startFileWatch()
+
+	_, ok := c.getFlagConfig("a")
+	assert.True(t, ok)
+	assert.NotNil(t, c.watcherStop)
+
+	c.Close()
+	assert.True(t, watcher.stopped)
+}
+
+type erroringWatcher struct{}
+
+func (w *erroringWatcher) Watch(path string, onChange func(), onError func(error)) (func(), error) {
+	return nil, assert.AnError
+}
+
+func TestStartFileWatchReportsSetupError(t *testing.T) {
+	var reported error
+	c := NewClient("key", &Config{
+		BootstrapFile: "flags.json",
+		Watcher:       &erroringWatcher{},
+		OnError:       func(err error) { reported = err },
+	})
+
+	c.startFileWatch()
+
+	assert.Error(t, reported)
+	assert.Nil(t, c.watcherStop)
+}
+
+func TestInitializeOfflineModeStartsWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"flags":{"a":{"key":"a","enabled":true}}}`), 0o644))
+
+	watcher := &fakeWatcher{}
+	c := NewClient("key", &Config{
+		BootstrapFile: path,
+		OfflineMode:   true,
+		Watcher:       watcher,
+	})
+
+	assert.NoError(t, c.Initialize())
+	defer c.Close()
+
+	_, ok := c.getFlagConfig("a")
+	assert.True(t, ok)
+	assert.NotNil(t, c.watcherStop)
+}
@@ -0,0 +1,263 @@
+package toggletown
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StreamingMode controls how the client receives flag updates from the API.
+type StreamingMode int
+
+const (
+	// StreamingModePoll refreshes flags on a fixed interval (the default).
+	StreamingModePoll StreamingMode = iota
+	// StreamingModeStream keeps a long-lived connection open and applies
+	// updates as they're pushed by the server.
+	StreamingModeStream
+	// StreamingModeStreamWithPollFallback streams updates but falls back to
+	// polling whenever the stream connection is down.
+	StreamingModeStreamWithPollFallback
+)
+
+const (
+	streamInitialBackoff = 250 * time.Millisecond
+	streamMaxBackoff     = 30 * time.Second
+)
+
+// streamPatch is a single-flag update pushed over the stream, as opposed to
+// a full FlagsResponse snapshot.
+type streamPatch struct {
+	Key    string     `json:"key"`
+	Config FlagConfig `json:"config"`
+}
+
+func (c *Client) startStreaming() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.streamCancel = cancel
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.streamFlags(ctx)
+	}()
+}
+
+// streamFlags keeps a long-lived connection to the flags stream open,
+// reconnecting with exponential backoff and jitter until ctx is canceled.
+// Backoff resets to streamInitialBackoff on every successful connection, so
+// a server that cycles connections periodically (e.g. behind a proxy or
+// load balancer) doesn't get ratcheted up to the max delay permanently.
+func (c *Client) streamFlags(ctx context.Context) {
+	backoff := streamInitialBackoff
+	resetBackoff := func() { backoff = streamInitialBackoff }
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.stopChan:
+			return
+		default:
+		}
+
+		err := c.connectStream(ctx, resetBackoff)
+		if err == nil {
+			return
+		}
+
+		if c.onError != nil {
+			c.onError(err)
+		}
+		c.checkStaleness()
+
+		if c.streamingMode == StreamingModeStreamWithPollFallback {
+			c.startPollFallback()
+		}
+
+		wait := jitteredWait(backoff)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		case <-c.stopChan:
+			return
+		}
+
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+// jitteredWait adds up to 100% jitter to backoff, clamped to
+// streamMaxBackoff so the jitter itself can't push the delay past the
+// documented cap.
+func jitteredWait(backoff time.Duration) time.Duration {
+	wait := backoff + time.Duration(rand.Int63n(int64(backoff)+1))
+	if wait > streamMaxBackoff {
+		wait = streamMaxBackoff
+	}
+	return wait
+}
+
+// connectStream opens the SSE connection and applies frames until it's
+// closed or ctx is canceled. A nil error means the caller should stop
+// retrying (clean shutdown); any other error means the caller should
+// reconnect. onConnected is called once the connection is established, so
+// the caller can reset its reconnect backoff.
+func (c *Client) connectStream(ctx context.Context, onConnected func()) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.apiURL+"/api/v1/sdk/flags/stream", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create stream request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to open flag stream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("stream API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	c.onStreamConnected()
+	onConnected()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+		if err := c.applyStreamFrame([]byte(payload)); err != nil && c.onError != nil {
+			c.onError(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("flag stream closed: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("flag stream closed by server")
+}
+
+// applyStreamFrame decodes a single SSE data frame, which is either a full
+// FlagsResponse snapshot or a {"key", "config"} patch event, and merges it
+// into the cached flags.
+func (c *Client) applyStreamFrame(data []byte) error {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return fmt.Errorf("failed to decode stream frame: %w", err)
+	}
+
+	if _, ok := probe["flags"]; ok {
+		var snapshot FlagsResponse
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return fmt.Errorf("failed to decode flags snapshot: %w", err)
+		}
+		now := time.Now()
+		c.mu.Lock()
+		c.flags = snapshot.Flags
+		c.lastUpdatedAt = now
+		c.staleFired = false
+		c.mu.Unlock()
+		c.saveToCacheAsync(snapshot, now)
+		return nil
+	}
+
+	var patch streamPatch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return fmt.Errorf("failed to decode flag patch: %w", err)
+	}
+	if patch.Key == "" {
+		return fmt.Errorf("flag patch missing key")
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	if c.flags == nil {
+		c.flags = make(map[string]FlagConfig)
+	}
+	c.flags[patch.Key] = patch.Config
+	flags := make(map[string]FlagConfig, len(c.flags))
+	for k, v := range c.flags {
+		flags[k] = v
+	}
+	c.lastUpdatedAt = now
+	c.staleFired = false
+	c.mu.Unlock()
+	c.saveToCacheAsync(FlagsResponse{Flags: flags}, now)
+	return nil
+}
+
+// onStreamConnected stops the polling fallback, if one was started while the
+// stream was down.
+func (c *Client) onStreamConnected() {
+	c.mu.Lock()
+	stop := c.pollFallbackCancel
+	c.pollFallbackCancel = nil
+	c.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// startPollFallback begins polling on the configured interval until the
+// stream reconnects or the client is closed. It's a no-op if fallback
+// polling is already running.
+func (c *Client) startPollFallback() {
+	c.mu.Lock()
+	if c.pollFallbackCancel != nil {
+		c.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	c.pollFallbackCancel = func() { close(stop) }
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.pollingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.fetchFlags()
+			case <-stop:
+				return
+			case <-c.stopChan:
+				return
+			}
+		}
+	}()
+}
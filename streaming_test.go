@@ -0,0 +1,235 @@
+package toggletown
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyStreamFrame(t *testing.T) {
+	t.Run("full snapshot replaces flags", func(t *testing.T) {
+		c := NewClient("key", nil)
+		c.flags = map[string]FlagConfig{"old": {Key: "old"}}
+
+		err := c.applyStreamFrame([]byte(`{"flags":{"new-flag":{"key":"new-flag","enabled":true}}}`))
+		assert.NoError(t, err)
+
+		_, hasOld := c.getFlagConfig("old")
+		assert.False(t, hasOld)
+		config, ok := c.getFlagConfig("new-flag")
+		assert.True(t, ok)
+		assert.True(t, config.Enabled)
+	})
+
+	t.Run("patch merges a single flag without dropping others", func(t *testing.T) {
+		c := NewClient("key", nil)
+		c.flags = map[string]FlagConfig{"existing": {Key: "existing", Enabled: true}}
+
+		err := c.applyStreamFrame([]byte(`{"key":"new-flag","config":{"key":"new-flag","enabled":true}}`))
+		assert.NoError(t, err)
+
+		_, ok := c.getFlagConfig("existing")
+		assert.True(t, ok)
+		config, ok := c.getFlagConfig("new-flag")
+		assert.True(t, ok)
+		assert.True(t, config.Enabled)
+	})
+
+	t.Run("patch applies cleanly when flags is nil", func(t *testing.T) {
+		c := NewClient("key", nil)
+		c.flags = nil
+
+		err := c.applyStreamFrame([]byte(`{"key":"new-flag","config":{"key":"new-flag","enabled":true}}`))
+		assert.NoError(t, err)
+
+		config, ok := c.getFlagConfig("new-flag")
+		assert.True(t, ok)
+		assert.True(t, config.Enabled)
+	})
+
+	t.Run("patch missing key is an error", func(t *testing.T) {
+		c := NewClient("key", nil)
+		c.flags = map[string]FlagConfig{}
+		err := c.applyStreamFrame([]byte(`{"config":{"enabled":true}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid JSON is an error", func(t *testing.T) {
+		c := NewClient("key", nil)
+		err := c.applyStreamFrame([]byte(`not json`))
+		assert.Error(t, err)
+	})
+
+	t.Run("persists to cache on snapshot and patch", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := &FileCache{Path: dir + "/cache.json"}
+		c := NewClient("key", &Config{Cache: cache})
+		c.flags = map[string]FlagConfig{}
+
+		assert.NoError(t, c.applyStreamFrame([]byte(`{"flags":{"a":{"key":"a","enabled":true}}}`)))
+		c.wg.Wait()
+		_, _, err := cache.Load()
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.applyStreamFrame([]byte(`{"key":"b","config":{"key":"b","enabled":true}}`)))
+		c.wg.Wait()
+		flagsResp, _, err := cache.Load()
+		assert.NoError(t, err)
+		assert.Contains(t, flagsResp.Flags, "a")
+		assert.Contains(t, flagsResp.Flags, "b")
+	})
+}
+
+func TestConnectStream(t *testing.T) {
+	t.Run("applies a snapshot frame then reports the server closing", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"flags\":{\"a\":{\"key\":\"a\",\"enabled\":true}}}\n\n")
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL})
+		err := c.connectStream(context.Background(), func() {})
+		assert.Error(t, err)
+
+		config, ok := c.getFlagConfig("a")
+		assert.True(t, ok)
+		assert.True(t, config.Enabled)
+	})
+
+	t.Run("returns nil when ctx is already canceled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := c.connectStream(ctx, func() {})
+		assert.NoError(t, err)
+	})
+
+	t.Run("non-200 status is an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL})
+		err := c.connectStream(context.Background(), func() {})
+		assert.Error(t, err)
+	})
+
+	t.Run("calls onConnected once the connection is established", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL})
+		connected := false
+		_ = c.connectStream(context.Background(), func() { connected = true })
+
+		assert.True(t, connected)
+	})
+
+	t.Run("does not call onConnected on a non-200 status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		c := NewClient("key", &Config{APIURL: server.URL})
+		connected := false
+		_ = c.connectStream(context.Background(), func() { connected = true })
+
+		assert.False(t, connected)
+	})
+}
+
+func TestStreamFlagsReconnectsOnError(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"flags\":{\"a\":{\"key\":\"a\",\"enabled\":true}}}\n\n")
+	}))
+	defer server.Close()
+
+	var errs []error
+	c := NewClient("key", &Config{
+		APIURL:  server.URL,
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c.streamFlags(ctx)
+
+	assert.GreaterOrEqual(t, attempts, 3)
+	assert.NotEmpty(t, errs)
+	config, ok := c.getFlagConfig("a")
+	assert.True(t, ok)
+	assert.True(t, config.Enabled)
+}
+
+func TestJitteredWait(t *testing.T) {
+	t.Run("never exceeds streamMaxBackoff even with jitter", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			wait := jitteredWait(streamMaxBackoff)
+			assert.LessOrEqual(t, wait, streamMaxBackoff)
+		}
+	})
+
+	t.Run("is never less than backoff", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			wait := jitteredWait(streamInitialBackoff)
+			assert.GreaterOrEqual(t, wait, streamInitialBackoff)
+		}
+	})
+}
+
+func TestStreamFlagsResetsBackoffAfterSuccess(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts%2 == 0 {
+			w.Header().Set("Content-Type", "text/event-stream")
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := NewClient("key", &Config{APIURL: server.URL, OnError: func(error) {}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	c.streamFlags(ctx)
+
+	// Every other connection succeeds and resets the backoff, so without the
+	// reset fix this count would stay low as backoff grows unbounded toward
+	// streamMaxBackoff.
+	assert.GreaterOrEqual(t, attempts, 5)
+}
+
+func TestOnStreamConnectedStopsPollFallback(t *testing.T) {
+	c := NewClient("key", nil)
+	stopped := false
+	c.pollFallbackCancel = func() { stopped = true }
+
+	c.onStreamConnected()
+
+	assert.True(t, stopped)
+	assert.Nil(t, c.pollFallbackCancel)
+}
@@ -0,0 +1,58 @@
+// Package fsnotify implements toggletown.FileWatcher on top of fsnotify, so
+// the core module doesn't have to depend on a filesystem-watching library
+// for applications that don't configure a Watcher.
+package fsnotify
+
+import (
+	"fmt"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher implements toggletown.FileWatcher using fsnotify.
+type Watcher struct{}
+
+// Watch starts watching path, calling onChange on every write/create event
+// and onError on watch errors, until the returned stop function is called.
+func (w *Watcher) Watch(path string, onChange func(), onError func(error)) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	if err := watcher.Add(path); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				onChange()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				if onError != nil {
+					onError(fmt.Errorf("file watcher error: %w", err))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+	}
+	return stop, nil
+}